@@ -0,0 +1,140 @@
+/**
+ *
+ * Copyright 2017 Google Inc. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package currencyconverter
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"google.golang.org/appengine"
+	"google.golang.org/appengine/log"
+)
+
+// rateCacheTTL is how long cached rates are served before being considered
+// stale; it matches the Cache-Control header already set on the /rates
+// response.
+const rateCacheTTL = 6 * time.Hour
+
+// rateCacheRefreshInterval is how often the background goroutine started in
+// init tries to refresh latestRateCache.
+const rateCacheRefreshInterval = 1 * time.Hour
+
+// RateCache holds the most recently fetched ECB rates so that handlers don't
+// each trigger their own outbound fetch — the ECB only republishes once a
+// day.
+type RateCache struct {
+	mu            sync.RWMutex
+	rates         Rates
+	fetchedAt     time.Time
+	failedRefresh bool
+
+	// fetch retrieves the latest rates; it is a field, rather than a direct
+	// call to fetchECBLatest, so tests can substitute a fake.
+	fetch func(context.Context) (Rates, error)
+
+	// logf reports a refresh failure; it is a field, rather than a direct
+	// call to log.Errorf, so tests can substitute a fake instead of needing a
+	// real App Engine context.
+	logf func(ctx context.Context, format string, args ...interface{})
+}
+
+// latestRateCache is populated by the background goroutine started in init
+// and read by ecbProvider.FetchLatest.
+var latestRateCache = &RateCache{fetch: fetchECBLatest, logf: log.Errorf}
+
+func init() {
+	go latestRateCache.refreshLoop()
+}
+
+// refreshLoop refreshes the cache immediately, then on every tick of
+// rateCacheRefreshInterval, for the lifetime of the instance.
+func (c *RateCache) refreshLoop() {
+	ctx := appengine.BackgroundContext()
+
+	c.refresh(ctx)
+
+	ticker := time.NewTicker(rateCacheRefreshInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		c.refresh(ctx)
+	}
+}
+
+// refresh fetches the latest ECB rates and stores them. A failed fetch
+// leaves the previous rates in place so the cache keeps serving stale data
+// rather than erroring out.
+func (c *RateCache) refresh(ctx context.Context) {
+	rates, err := c.fetch(ctx)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err != nil {
+		c.logf(ctx, "refreshing ECB rate cache: %v", err)
+		c.failedRefresh = true
+		return
+	}
+
+	c.rates = rates
+	c.fetchedAt = time.Now()
+	c.failedRefresh = false
+}
+
+// get returns the cached rates. On a true cache miss — a request landing
+// before the background refreshLoop has completed its first fetch, e.g. on
+// a cold App Engine instance — it falls back to fetching synchronously
+// rather than failing the request.
+func (c *RateCache) get(ctx context.Context) (Rates, error) {
+	c.mu.RLock()
+	populated := !c.fetchedAt.IsZero()
+	c.mu.RUnlock()
+
+	if !populated {
+		c.refresh(ctx)
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.fetchedAt.IsZero() {
+		return Rates{}, fmt.Errorf("ecb: rate cache not yet populated")
+	}
+
+	return c.rates, nil
+}
+
+// Stale reports whether the cached rates are older than rateCacheTTL, or
+// whether the most recent background refresh attempt failed.
+func (c *RateCache) Stale() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.fetchedAt.IsZero() {
+		return true
+	}
+
+	return c.failedRefresh || time.Since(c.fetchedAt) >= rateCacheTTL
+}
+
+// staleChecker is implemented by providers that can report whether their
+// last successful fetch is being served past its freshness window.
+type staleChecker interface {
+	Stale() bool
+}
@@ -0,0 +1,152 @@
+/**
+ *
+ * Copyright 2017 Google Inc. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package currencyconverter
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestRateCacheStale(t *testing.T) {
+	tests := []struct {
+		name          string
+		fetchedAt     time.Time
+		failedRefresh bool
+		want          bool
+	}{
+		{
+			name:      "never populated is stale",
+			fetchedAt: time.Time{},
+			want:      true,
+		},
+		{
+			name:      "fresh fetch is not stale",
+			fetchedAt: time.Now(),
+			want:      false,
+		},
+		{
+			name:      "fetch older than the TTL is stale",
+			fetchedAt: time.Now().Add(-2 * rateCacheTTL),
+			want:      true,
+		},
+		{
+			name:          "a failed refresh is stale even with a fresh fetch",
+			fetchedAt:     time.Now(),
+			failedRefresh: true,
+			want:          true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &RateCache{fetchedAt: tt.fetchedAt, failedRefresh: tt.failedRefresh}
+			if got := c.Stale(); got != tt.want {
+				t.Errorf("Stale() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRateCacheRefreshKeepsStaleDataOnFailure(t *testing.T) {
+	want := Rates{Base: "EUR", Date: "2024-01-01"}
+	fetchedAt := time.Now().Add(-time.Hour)
+
+	c := &RateCache{
+		rates:     want,
+		fetchedAt: fetchedAt,
+		fetch: func(context.Context) (Rates, error) {
+			return Rates{}, fmt.Errorf("ecb: fetch failed")
+		},
+		logf: func(context.Context, string, ...interface{}) {},
+	}
+
+	c.refresh(context.Background())
+
+	if c.rates.Base != want.Base || c.rates.Date != want.Date {
+		t.Errorf("refresh() on a failed fetch changed rates to %v, want unchanged %v", c.rates, want)
+	}
+	if !c.fetchedAt.Equal(fetchedAt) {
+		t.Errorf("refresh() on a failed fetch changed fetchedAt to %v, want unchanged %v", c.fetchedAt, fetchedAt)
+	}
+	if !c.failedRefresh {
+		t.Error("refresh() on a failed fetch left failedRefresh false, want true")
+	}
+}
+
+func TestRateCacheGetColdStartFallsBackSynchronously(t *testing.T) {
+	want := Rates{Base: "EUR", Date: "2024-01-01"}
+	calls := 0
+
+	c := &RateCache{
+		fetch: func(context.Context) (Rates, error) {
+			calls++
+			return want, nil
+		},
+	}
+
+	got, err := c.get(context.Background())
+	if err != nil {
+		t.Fatalf("get() on a cold cache returned unexpected error: %v", err)
+	}
+	if got.Base != want.Base || got.Date != want.Date {
+		t.Errorf("get() on a cold cache = %v, want %v", got, want)
+	}
+	if calls != 1 {
+		t.Errorf("get() on a cold cache called fetch %d times, want 1", calls)
+	}
+}
+
+func TestRateCacheGetReturnsCachedRatesWithoutFetching(t *testing.T) {
+	want := Rates{Base: "EUR", Date: "2024-01-01"}
+	calls := 0
+
+	c := &RateCache{
+		rates:     want,
+		fetchedAt: time.Now(),
+		fetch: func(context.Context) (Rates, error) {
+			calls++
+			return Rates{}, fmt.Errorf("should not be called")
+		},
+	}
+
+	got, err := c.get(context.Background())
+	if err != nil {
+		t.Fatalf("get() on a populated cache returned unexpected error: %v", err)
+	}
+	if got.Base != want.Base || got.Date != want.Date {
+		t.Errorf("get() on a populated cache = %v, want %v", got, want)
+	}
+	if calls != 0 {
+		t.Errorf("get() on a populated cache called fetch %d times, want 0", calls)
+	}
+}
+
+func TestRateCacheGetColdStartPropagatesFetchError(t *testing.T) {
+	c := &RateCache{
+		fetch: func(context.Context) (Rates, error) {
+			return Rates{}, fmt.Errorf("ecb: fetch failed")
+		},
+		logf: func(context.Context, string, ...interface{}) {},
+	}
+
+	if _, err := c.get(context.Background()); err == nil {
+		t.Error("get() on a cold cache with a failing fetch = nil error, want error")
+	}
+}
@@ -0,0 +1,208 @@
+/**
+ *
+ * Copyright 2017 Google Inc. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package currencyconverter
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"google.golang.org/appengine"
+)
+
+// symbolAliases maps the free-form currency symbols and names accepted by
+// the q= parameter to their ISO 4217 codes.
+var symbolAliases = map[string]string{
+	"$":       "USD",
+	"dollar":  "USD",
+	"dollars": "USD",
+	"€":       "EUR",
+	"euro":    "EUR",
+	"euros":   "EUR",
+	"£":       "GBP",
+	"pound":   "GBP",
+	"pounds":  "GBP",
+	"¥":       "JPY",
+	"yen":     "JPY",
+}
+
+// freeFormPattern splits a "100 USD" or "100$" token into its amount and
+// symbol parts.
+var freeFormPattern = regexp.MustCompile(`^\s*([0-9]*\.?[0-9]+)\s*([^\s0-9]+)\s*$`)
+
+// conversionInput is a single amount/currency pair to convert, either parsed
+// from amount= and from=, or tokenized out of q=.
+type conversionInput struct {
+	Amount float64
+	From   string
+}
+
+// convertItem is the conversion of a single amount from From into Results.
+type convertItem struct {
+	From    string             `json:"from"`
+	Amount  float64            `json:"amount"`
+	Results map[string]float64 `json:"results"`
+}
+
+// convertResponse wraps multiple convertItems, as produced by a q= with more
+// than one amount/symbol pair.
+type convertResponse struct {
+	Items []convertItem `json:"items"`
+}
+
+func init() {
+	http.HandleFunc("/convert", convertHandler)
+}
+
+func convertHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := appengine.NewContext(r)
+
+	p, err := providerFor(r.URL.Query().Get("source"))
+	if err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+
+	latest, err := p.FetchLatest(ctx)
+	if err != nil {
+		http.Error(w, "Error retrieving rates", 500)
+		return
+	}
+	rates := latest.RateMap
+
+	toParam := r.URL.Query().Get("to")
+	if toParam == "" {
+		http.Error(w, "Missing to", 400)
+		return
+	}
+	targets := strings.Split(toParam, ",")
+	for i, t := range targets {
+		targets[i] = strings.ToUpper(strings.TrimSpace(t))
+	}
+
+	var inputs []conversionInput
+	if q := r.URL.Query().Get("q"); q != "" {
+		inputs, err = parseFreeForm(q)
+		if err != nil {
+			http.Error(w, err.Error(), 400)
+			return
+		}
+	} else {
+		amountStr := r.URL.Query().Get("amount")
+		from := strings.ToUpper(r.URL.Query().Get("from"))
+		if amountStr == "" || from == "" {
+			http.Error(w, "Missing amount or from", 400)
+			return
+		}
+		amount, err := strconv.ParseFloat(amountStr, 64)
+		if err != nil {
+			http.Error(w, "Invalid amount", 400)
+			return
+		}
+		inputs = []conversionInput{{Amount: amount, From: from}}
+	}
+
+	items := make([]convertItem, 0, len(inputs))
+	for _, in := range inputs {
+		results := make(map[string]float64, len(targets))
+		for _, to := range targets {
+			v, err := convertAmount(rates, latest.Base, in.Amount, in.From, to)
+			if err != nil {
+				http.Error(w, err.Error(), 400)
+				return
+			}
+			results[to] = v
+		}
+		items = append(items, convertItem{From: in.From, Amount: in.Amount, Results: results})
+	}
+
+	if len(items) == 1 {
+		writeJSON(w, items[0])
+		return
+	}
+	writeJSON(w, convertResponse{Items: items})
+}
+
+// parseFreeForm tokenizes a comma-separated free-form query such as
+// "15.5 euro, 100$" into conversionInputs.
+func parseFreeForm(q string) ([]conversionInput, error) {
+	tokens := strings.Split(q, ",")
+	inputs := make([]conversionInput, 0, len(tokens))
+
+	for _, token := range tokens {
+		matches := freeFormPattern.FindStringSubmatch(token)
+		if matches == nil {
+			return nil, fmt.Errorf("could not parse %q", strings.TrimSpace(token))
+		}
+
+		amount, err := strconv.ParseFloat(matches[1], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid amount in %q", strings.TrimSpace(token))
+		}
+
+		from, err := resolveSymbol(matches[2])
+		if err != nil {
+			return nil, err
+		}
+
+		inputs = append(inputs, conversionInput{Amount: amount, From: from})
+	}
+
+	return inputs, nil
+}
+
+// resolveSymbol maps a currency symbol, lowercase alias, or ISO code to its
+// ISO 4217 code.
+func resolveSymbol(symbol string) (string, error) {
+	if code, ok := symbolAliases[symbol]; ok {
+		return code, nil
+	}
+	if code, ok := symbolAliases[strings.ToLower(symbol)]; ok {
+		return code, nil
+	}
+	if upper := strings.ToUpper(symbol); len(upper) == 3 {
+		return upper, nil
+	}
+
+	return "", fmt.Errorf("unrecognized currency %q", symbol)
+}
+
+// convertAmount cross-rates amount from one currency to another through the
+// anchor-currency rates map returned by a Provider.
+func convertAmount(rates map[string]float32, anchor string, amount float64, from, to string) (float64, error) {
+	if from != anchor {
+		fromRate, ok := rates[from]
+		if !ok {
+			return 0, fmt.Errorf("unknown currency %q", from)
+		}
+		amount = amount / float64(fromRate)
+	}
+
+	if to == anchor {
+		return amount, nil
+	}
+
+	toRate, ok := rates[to]
+	if !ok {
+		return 0, fmt.Errorf("unknown currency %q", to)
+	}
+
+	return amount * float64(toRate), nil
+}
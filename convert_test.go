@@ -0,0 +1,188 @@
+/**
+ *
+ * Copyright 2017 Google Inc. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package currencyconverter
+
+import (
+	"math"
+	"testing"
+)
+
+func TestResolveSymbol(t *testing.T) {
+	tests := []struct {
+		symbol  string
+		want    string
+		wantErr bool
+	}{
+		{symbol: "$", want: "USD"},
+		{symbol: "€", want: "EUR"},
+		{symbol: "£", want: "GBP"},
+		{symbol: "¥", want: "JPY"},
+		{symbol: "dollar", want: "USD"},
+		{symbol: "Euro", want: "EUR"},
+		{symbol: "usd", want: "USD"},
+		{symbol: "JPY", want: "JPY"},
+		{symbol: "????", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.symbol, func(t *testing.T) {
+			got, err := resolveSymbol(tt.symbol)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("resolveSymbol(%q) = %q, nil, want error", tt.symbol, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resolveSymbol(%q) returned unexpected error: %v", tt.symbol, err)
+			}
+			if got != tt.want {
+				t.Errorf("resolveSymbol(%q) = %q, want %q", tt.symbol, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseFreeForm(t *testing.T) {
+	tests := []struct {
+		name    string
+		q       string
+		want    []conversionInput
+		wantErr bool
+	}{
+		{
+			name: "single dollar sign token",
+			q:    "100$",
+			want: []conversionInput{{Amount: 100, From: "USD"}},
+		},
+		{
+			name: "multiple comma-separated tokens with aliases",
+			q:    "15.5 euro, 100$",
+			want: []conversionInput{
+				{Amount: 15.5, From: "EUR"},
+				{Amount: 100, From: "USD"},
+			},
+		},
+		{
+			name:    "unparseable token errors",
+			q:       "not a token",
+			wantErr: true,
+		},
+		{
+			name:    "unrecognized currency errors",
+			q:       "100 zz",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseFreeForm(tt.q)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parseFreeForm(%q) = %v, nil, want error", tt.q, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseFreeForm(%q) returned unexpected error: %v", tt.q, err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("parseFreeForm(%q) = %v, want %v", tt.q, got, tt.want)
+			}
+			for i, want := range tt.want {
+				if got[i] != want {
+					t.Errorf("parseFreeForm(%q)[%d] = %v, want %v", tt.q, i, got[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestConvertAmount(t *testing.T) {
+	rates := map[string]float32{"USD": 1.1, "GBP": 0.88, "JPY": 121.0}
+
+	tests := []struct {
+		name    string
+		anchor  string
+		amount  float64
+		from    string
+		to      string
+		want    float64
+		wantErr bool
+	}{
+		{
+			name:   "anchor to target",
+			anchor: "EUR",
+			amount: 100,
+			from:   "EUR",
+			to:     "USD",
+			want:   110,
+		},
+		{
+			name:   "non-anchor to anchor",
+			anchor: "EUR",
+			amount: 110,
+			from:   "USD",
+			to:     "EUR",
+			want:   100,
+		},
+		{
+			name:   "cross-rating two non-anchor currencies",
+			anchor: "EUR",
+			amount: 110,
+			from:   "USD",
+			to:     "GBP",
+			want:   100 * 0.88,
+		},
+		{
+			name:    "unknown source currency errors",
+			anchor:  "EUR",
+			amount:  100,
+			from:    "XYZ",
+			to:      "USD",
+			wantErr: true,
+		},
+		{
+			name:    "unknown target currency errors",
+			anchor:  "EUR",
+			amount:  100,
+			from:    "EUR",
+			to:      "XYZ",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := convertAmount(rates, tt.anchor, tt.amount, tt.from, tt.to)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("convertAmount(...) = %v, nil, want error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("convertAmount(...) returned unexpected error: %v", err)
+			}
+			if math.Abs(got-tt.want) > 1e-3 {
+				t.Errorf("convertAmount(...) = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,239 @@
+/**
+ *
+ * Copyright 2017 Google Inc. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package currencyconverter
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"sync"
+	"time"
+
+	"google.golang.org/appengine/urlfetch"
+)
+
+// RateURL is the endpoint for European Central Bank daily rates.
+const RateURL = "https://www.ecb.europa.eu/stats/eurofxref/eurofxref-daily.xml"
+
+// Hist90URL is the ECB feed covering the last 90 days of published rates.
+const Hist90URL = "https://www.ecb.europa.eu/stats/eurofxref/eurofxref-hist-90d.xml"
+
+// HistURL is the ECB feed covering the full history of published rates.
+const HistURL = "https://www.ecb.europa.eu/stats/eurofxref/eurofxref-hist.xml"
+
+// ecbProviderName is the Provider.Name() of the ECB provider and the value
+// of the ?source= and RATES_PROVIDER settings that select it.
+const ecbProviderName = "ecb"
+
+// ecbProvider fetches rates from the European Central Bank's published XML
+// feeds. All ECB rates are anchored on EUR.
+type ecbProvider struct{}
+
+func init() {
+	registerProvider(ecbProvider{})
+}
+
+func (ecbProvider) Name() string { return ecbProviderName }
+
+func (ecbProvider) SupportedBases() []string { return []string{"EUR"} }
+
+// Stale reports whether latestRateCache is being served past its freshness
+// window; it satisfies staleChecker (see cache.go).
+func (ecbProvider) Stale() bool { return latestRateCache.Stale() }
+
+func (p ecbProvider) FetchLatest(ctx context.Context) (Rates, error) {
+	rates, err := latestRateCache.get(ctx)
+	if err != nil {
+		return Rates{}, err
+	}
+
+	rates.Source = p.Name()
+
+	return rates, nil
+}
+
+// fetchECBLatest fetches and parses the ECB daily feed directly, bypassing
+// latestRateCache. It is called by the cache's background refresh loop (see
+// cache.go).
+func fetchECBLatest(ctx context.Context) (Rates, error) {
+	start := time.Now()
+
+	body, err := fetchURL(ctx, RateURL)
+	if err != nil {
+		recordFetch(ctx, ecbProviderName, start, 0, 0, err)
+		return Rates{}, err
+	}
+
+	x := Envelope{}
+	if err := xml.Unmarshal(body, &x); err != nil {
+		recordParseError(ecbProviderName)
+		recordFetch(ctx, ecbProviderName, start, len(body), 0, err)
+		return Rates{}, err
+	}
+	if len(x.Cubes) == 0 {
+		err := fmt.Errorf("no rates in feed")
+		recordParseError(ecbProviderName)
+		recordFetch(ctx, ecbProviderName, start, len(body), 0, err)
+		return Rates{}, err
+	}
+
+	rates := populateRateMap(x.Cubes[0])
+	rates.FetchedAt = time.Now()
+
+	recordFetch(ctx, ecbProviderName, start, len(body), len(rates.RateMap), nil)
+
+	return rates, nil
+}
+
+func (p ecbProvider) FetchHistorical(ctx context.Context, date string) (Rates, error) {
+	if err := ensureHistoryLoaded(ctx, date); err != nil {
+		return Rates{}, err
+	}
+
+	historyCache.mu.RLock()
+	rates, ok := historyCache.byDate[date]
+	historyCache.mu.RUnlock()
+	if !ok {
+		return Rates{}, fmt.Errorf("unknown date %q", date)
+	}
+
+	rates.Source = p.Name()
+
+	return rates, nil
+}
+
+// historyCacheTTL is how long a historyCache load is trusted before
+// ensureHistoryLoaded will reload the 90-day feed to pick up newly published
+// dates; it mirrors rateCacheTTL (see cache.go).
+const historyCacheTTL = 6 * time.Hour
+
+// historyCache holds rates parsed from the ECB historical feeds, keyed by
+// date, so that repeat requests don't re-fetch and re-parse the
+// multi-megabyte history payloads.
+var historyCache = struct {
+	mu       sync.RWMutex
+	byDate   map[string]Rates
+	loadedAt time.Time
+	// fullLoaded is set once HistURL, not just Hist90URL, has been merged in.
+	fullLoaded bool
+}{byDate: make(map[string]Rates)}
+
+// ensureHistoryLoaded populates historyCache from the 90-day feed, falling
+// back to the full history feed when date isn't found there (or when date is
+// empty, as for the timeseries handler, which needs the full range). It is a
+// no-op once a feed covering date has already been cached, unless that load
+// is older than historyCacheTTL, since the ECB appends a new day to its
+// feeds daily.
+func ensureHistoryLoaded(ctx context.Context, date string) error {
+	historyCache.mu.RLock()
+	_, cached := historyCache.byDate[date]
+	fullLoaded := historyCache.fullLoaded
+	fresh := !historyCache.loadedAt.IsZero() && time.Since(historyCache.loadedAt) < historyCacheTTL
+	historyCache.mu.RUnlock()
+
+	if date != "" && cached && fresh {
+		return nil
+	}
+	if date == "" && fullLoaded && fresh {
+		return nil
+	}
+
+	// HistURL is a strict superset of Hist90URL, so the timeseries handler
+	// (which always passes date == "" and needs the full range anyway) goes
+	// straight to it rather than fetching both feeds.
+	if date == "" {
+		return loadHistory(ctx, HistURL)
+	}
+
+	if err := loadHistory(ctx, Hist90URL); err != nil {
+		return err
+	}
+
+	historyCache.mu.RLock()
+	_, cached = historyCache.byDate[date]
+	historyCache.mu.RUnlock()
+
+	if cached {
+		return nil
+	}
+
+	return loadHistory(ctx, HistURL)
+}
+
+// loadHistory fetches and parses an ECB historical feed, merging its dates
+// into historyCache.
+func loadHistory(ctx context.Context, url string) error {
+	start := time.Now()
+
+	body, err := fetchURL(ctx, url)
+	if err != nil {
+		recordFetch(ctx, ecbProviderName, start, 0, 0, err)
+		return err
+	}
+
+	x := Envelope{}
+	if err := xml.Unmarshal(body, &x); err != nil {
+		recordParseError(ecbProviderName)
+		recordFetch(ctx, ecbProviderName, start, len(body), 0, err)
+		return err
+	}
+
+	fetchedAt := time.Now()
+
+	historyCache.mu.Lock()
+	for _, cube := range x.Cubes {
+		rates := populateRateMap(cube)
+		rates.FetchedAt = fetchedAt
+		historyCache.byDate[rates.Date] = rates
+	}
+	historyCache.loadedAt = fetchedAt
+	if url == HistURL {
+		historyCache.fullLoaded = true
+	}
+	historyCache.mu.Unlock()
+
+	recordFetch(ctx, ecbProviderName, start, len(body), len(x.Cubes), nil)
+
+	return nil
+}
+
+// populateRateMap sets Base and builds RateMap from RateList on a Rates
+// parsed out of an ECB feed.
+func populateRateMap(rates Rates) Rates {
+	rates.Base = "EUR"
+	rates.RateMap = make(map[string]float32, len(rates.RateList))
+	for _, rate := range rates.RateList {
+		rates.RateMap[rate.Currency] = rate.Rate
+	}
+
+	return rates
+}
+
+// fetchURL retrieves url's body through the App Engine URL fetch service.
+func fetchURL(ctx context.Context, url string) ([]byte, error) {
+	client := urlfetch.Client(ctx)
+
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	return ioutil.ReadAll(resp.Body)
+}
@@ -0,0 +1,93 @@
+/**
+ *
+ * Copyright 2017 Google Inc. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package currencyconverter
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+)
+
+// fixerProviderName is the Provider.Name() of the Fixer provider.
+const fixerProviderName = "fixer"
+
+// fixerAccessKeyEnv names the environment variable holding the Fixer API
+// access key.
+const fixerAccessKeyEnv = "FIXER_ACCESS_KEY"
+
+// fixerLatestURL and fixerHistoricalURL are Fixer's free-tier endpoints,
+// which are restricted to http and to EUR-based rates.
+const (
+	fixerLatestURL     = "http://data.fixer.io/api/latest"
+	fixerHistoricalURL = "http://data.fixer.io/api/"
+)
+
+// fixerProvider fetches rates from https://fixer.io. The free tier is
+// restricted to EUR-anchored rates, same as the ECB feeds it wraps.
+type fixerProvider struct{}
+
+func init() {
+	registerProvider(fixerProvider{})
+}
+
+func (fixerProvider) Name() string { return fixerProviderName }
+
+func (fixerProvider) SupportedBases() []string { return []string{"EUR"} }
+
+// fixerResponse mirrors the JSON shape returned by Fixer's endpoints.
+type fixerResponse struct {
+	Success bool               `json:"success"`
+	Date    string             `json:"date"`
+	Base    string             `json:"base"`
+	Rates   map[string]float32 `json:"rates"`
+	Error   struct {
+		Info string `json:"info"`
+	} `json:"error"`
+}
+
+func (p fixerProvider) FetchLatest(ctx context.Context) (Rates, error) {
+	return p.fetch(ctx, fixerLatestURL)
+}
+
+func (p fixerProvider) FetchHistorical(ctx context.Context, date string) (Rates, error) {
+	return p.fetch(ctx, fixerHistoricalURL+date)
+}
+
+func (p fixerProvider) fetch(ctx context.Context, url string) (Rates, error) {
+	accessKey := os.Getenv(fixerAccessKeyEnv)
+	if accessKey == "" {
+		return Rates{}, fmt.Errorf("fixer: %s is not set", fixerAccessKeyEnv)
+	}
+
+	var body fixerResponse
+	if err := fetchAndDecodeJSON(ctx, p.Name(), url+"?access_key="+accessKey, &body); err != nil {
+		return Rates{}, err
+	}
+	if !body.Success {
+		return Rates{}, fmt.Errorf("fixer: %s", body.Error.Info)
+	}
+
+	return Rates{
+		Base:      body.Base,
+		Date:      body.Date,
+		RateMap:   body.Rates,
+		Source:    p.Name(),
+		FetchedAt: time.Now(),
+	}, nil
+}
@@ -0,0 +1,102 @@
+/**
+ *
+ * Copyright 2017 Google Inc. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package currencyconverter
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+)
+
+// frankfurterProviderName is the Provider.Name() of the Frankfurter
+// provider.
+const frankfurterProviderName = "frankfurter"
+
+// frankfurterBaseEnv names the environment variable that picks the base
+// currency Frankfurter is queried with; it defaults to EUR, matching the
+// ECB data Frankfurter itself republishes.
+const frankfurterBaseEnv = "FRANKFURTER_BASE"
+
+// frankfurterLatestURL and frankfurterBaseURL are Frankfurter's endpoints;
+// historical rates are served off the bare base URL as
+// frankfurterBaseURL+"/"+date, not under /latest.
+const (
+	frankfurterLatestURL = "https://api.frankfurter.app/latest"
+	frankfurterBaseURL   = "https://api.frankfurter.app"
+)
+
+// frankfurterProvider fetches rates from https://www.frankfurter.app, a
+// free, keyless API that republishes ECB data and, unlike the ECB feeds
+// themselves, supports an arbitrary base currency.
+type frankfurterProvider struct{}
+
+func init() {
+	registerProvider(frankfurterProvider{})
+}
+
+func (frankfurterProvider) Name() string { return frankfurterProviderName }
+
+// SupportedBases reports the single base Frankfurter is queried with: the
+// FRANKFURTER_BASE setting, or EUR when it is unset.
+func (frankfurterProvider) SupportedBases() []string {
+	if base := os.Getenv(frankfurterBaseEnv); base != "" {
+		return []string{base}
+	}
+	return []string{"EUR"}
+}
+
+// frankfurterResponse mirrors the JSON shape returned by Frankfurter's
+// latest and historical endpoints.
+type frankfurterResponse struct {
+	Amount float64            `json:"amount"`
+	Base   string             `json:"base"`
+	Date   string             `json:"date"`
+	Rates  map[string]float32 `json:"rates"`
+}
+
+func (p frankfurterProvider) FetchLatest(ctx context.Context) (Rates, error) {
+	return p.fetch(ctx, frankfurterLatestURL)
+}
+
+func (p frankfurterProvider) FetchHistorical(ctx context.Context, date string) (Rates, error) {
+	return p.fetch(ctx, frankfurterBaseURL+"/"+date)
+}
+
+func (p frankfurterProvider) fetch(ctx context.Context, url string) (Rates, error) {
+	base := os.Getenv(frankfurterBaseEnv)
+	if base == "" {
+		base = "EUR"
+	}
+
+	var body frankfurterResponse
+	if err := fetchAndDecodeJSON(ctx, p.Name(), url+"?base="+base, &body); err != nil {
+		return Rates{}, err
+	}
+	if body.Base == "" {
+		return Rates{}, fmt.Errorf("frankfurter: empty response")
+	}
+
+	return Rates{
+		Base:      body.Base,
+		Date:      body.Date,
+		RateMap:   body.Rates,
+		Source:    p.Name(),
+		FetchedAt: time.Now(),
+	}, nil
+}
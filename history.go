@@ -0,0 +1,99 @@
+/**
+ *
+ * Copyright 2017 Google Inc. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package currencyconverter
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"google.golang.org/appengine"
+)
+
+func init() {
+	http.HandleFunc("/rates/history/", historyHandler)
+	http.HandleFunc("/rates/timeseries", timeseriesHandler)
+}
+
+// historyHandler serves a single day's rates from /rates/history/{date}.
+func historyHandler(w http.ResponseWriter, r *http.Request) {
+	date := strings.TrimPrefix(r.URL.Path, "/rates/history/")
+	if date == "" {
+		http.Error(w, "Missing date", 400)
+		return
+	}
+
+	ctx := appengine.NewContext(r)
+
+	p, err := providerFor(r.URL.Query().Get("source"))
+	if err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+
+	rates, err := p.FetchHistorical(ctx, date)
+	if err != nil {
+		http.Error(w, "Unknown date", 404)
+		return
+	}
+
+	writeJSON(w, rates)
+}
+
+// timeseriesHandler serves a date range of rates from
+// /rates/timeseries?start=...&end=.... It is backed by the ECB's history
+// cache directly, since Provider has no bulk range query.
+func timeseriesHandler(w http.ResponseWriter, r *http.Request) {
+	start := r.URL.Query().Get("start")
+	end := r.URL.Query().Get("end")
+	if start == "" || end == "" {
+		http.Error(w, "Missing start or end", 400)
+		return
+	}
+
+	ctx := appengine.NewContext(r)
+	if err := ensureHistoryLoaded(ctx, ""); err != nil {
+		http.Error(w, "Error retrieving rates", 500)
+		return
+	}
+
+	series := make(map[string]Rates)
+	historyCache.mu.RLock()
+	for date, rates := range historyCache.byDate {
+		if date >= start && date <= end {
+			series[date] = rates
+		}
+	}
+	historyCache.mu.RUnlock()
+
+	writeJSON(w, series)
+}
+
+// writeJSON serializes v to JSON and writes it to w, matching the headers
+// used by the other rates handlers.
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	j, err := json.Marshal(v)
+	if err != nil {
+		http.Error(w, "Error serializing to JSON", 500)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "public, max-age=21600")
+	w.Write(append(j, '\n'))
+}
@@ -0,0 +1,129 @@
+/**
+ *
+ * Copyright 2017 Google Inc. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package currencyconverter
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/appengine"
+	"google.golang.org/appengine/log"
+	"google.golang.org/appengine/urlfetch"
+)
+
+var (
+	fetchTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "rates_fetch_total",
+		Help: "Total upstream rate fetches, by source and status (ok, error).",
+	}, []string{"source", "status"})
+
+	parseErrorsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "rates_parse_errors_total",
+		Help: "Total upstream responses that failed to parse, by source.",
+	}, []string{"source"})
+
+	fetchLatencySeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "rates_fetch_latency_seconds",
+		Help:    "Latency of upstream rate fetches, by source.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"source"})
+
+	fetchResponseBytes = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "rates_fetch_response_bytes",
+		Help:    "Size of upstream rate fetch responses, by source.",
+		Buckets: prometheus.ExponentialBuckets(1<<10, 2, 12),
+	}, []string{"source"})
+
+	lastSuccessTimestamp = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "rates_last_success_timestamp_seconds",
+		Help: "Unix timestamp of the last successful upstream rate fetch, across all sources.",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(fetchTotal, parseErrorsTotal, fetchLatencySeconds, fetchResponseBytes, lastSuccessTimestamp)
+	http.Handle("/metrics", promhttp.Handler())
+}
+
+// recordFetch updates the Prometheus metrics for a single upstream fetch
+// and writes a matching structured log line, so ECB outages or stale data
+// can be alarmed on without scraping App Engine logs directly.
+func recordFetch(ctx context.Context, source string, start time.Time, bytes, currencies int, err error) {
+	elapsed := time.Since(start)
+	status := "ok"
+	if err != nil {
+		status = "error"
+	}
+
+	fetchTotal.WithLabelValues(source, status).Inc()
+	fetchLatencySeconds.WithLabelValues(source).Observe(elapsed.Seconds())
+
+	if err == nil {
+		if bytes > 0 {
+			fetchResponseBytes.WithLabelValues(source).Observe(float64(bytes))
+		}
+		lastSuccessTimestamp.Set(float64(time.Now().Unix()))
+	}
+
+	reqID := appengine.RequestID(ctx)
+	if err != nil {
+		log.Errorf(ctx, "level=error request_id=%s source=%s status=%s duration=%s err=%q", reqID, source, status, elapsed, err)
+		return
+	}
+	log.Infof(ctx, "level=info request_id=%s source=%s status=%s duration=%s bytes=%d currencies=%d", reqID, source, status, elapsed, bytes, currencies)
+}
+
+// recordParseError increments rates_parse_errors_total for source.
+func recordParseError(source string) {
+	parseErrorsTotal.WithLabelValues(source).Inc()
+}
+
+// fetchAndDecodeJSON fetches url through the App Engine URL fetch service,
+// decodes the JSON body into out, and records fetch metrics and structured
+// logs under source.
+func fetchAndDecodeJSON(ctx context.Context, source, url string, out interface{}) error {
+	start := time.Now()
+	client := urlfetch.Client(ctx)
+
+	resp, err := client.Get(url)
+	if err != nil {
+		recordFetch(ctx, source, start, 0, 0, err)
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		recordFetch(ctx, source, start, 0, 0, err)
+		return err
+	}
+
+	if err := json.Unmarshal(body, out); err != nil {
+		recordParseError(source)
+		recordFetch(ctx, source, start, len(body), 0, err)
+		return err
+	}
+
+	recordFetch(ctx, source, start, len(body), 0, nil)
+	return nil
+}
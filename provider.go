@@ -0,0 +1,84 @@
+/**
+ *
+ * Copyright 2017 Google Inc. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package currencyconverter
+
+import (
+	"context"
+	"fmt"
+	"os"
+)
+
+// Provider is implemented by each upstream rates source, so that handlers
+// can fetch rates without caring which one is configured.
+type Provider interface {
+	// FetchLatest retrieves the most recently published rates.
+	FetchLatest(ctx context.Context) (Rates, error)
+	// FetchHistorical retrieves the rates published on date (YYYY-MM-DD).
+	FetchHistorical(ctx context.Context, date string) (Rates, error)
+	// Name identifies the provider, e.g. for the Rates.Source field and the
+	// ?source= query parameter.
+	Name() string
+	// SupportedBases lists the currencies a provider can natively quote
+	// against. Providers, like the ECB, that exclusively publish
+	// EUR-anchored rates return []string{"EUR"}.
+	SupportedBases() []string
+}
+
+// providers holds every registered Provider, keyed by Name().
+var providers = map[string]Provider{}
+
+// registerProvider adds a Provider to the registry. It is called from each
+// provider's init().
+func registerProvider(p Provider) {
+	providers[p.Name()] = p
+}
+
+// defaultProviderEnv names the environment variable used to choose the
+// default Provider when a request doesn't pass ?source=.
+const defaultProviderEnv = "RATES_PROVIDER"
+
+// providerFor resolves the requested source to a registered Provider. An
+// empty source falls back to the RATES_PROVIDER environment variable, then
+// to the ECB.
+func providerFor(source string) (Provider, error) {
+	if source == "" {
+		source = os.Getenv(defaultProviderEnv)
+	}
+	if source == "" {
+		source = ecbProviderName
+	}
+
+	p, ok := providers[source]
+	if !ok {
+		return nil, fmt.Errorf("unknown rate source %q", source)
+	}
+
+	return p, nil
+}
+
+// supportsBase reports whether base is among the currencies p natively
+// quotes against, per p.SupportedBases().
+func supportsBase(p Provider, base string) bool {
+	for _, b := range p.SupportedBases() {
+		if b == base {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,108 @@
+/**
+ *
+ * Copyright 2017 Google Inc. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package currencyconverter
+
+import (
+	"context"
+	"os"
+	"testing"
+)
+
+// fakeProvider is a minimal Provider for exercising providerFor/supportsBase
+// without depending on the registered real providers' env vars or network
+// access.
+type fakeProvider struct {
+	name  string
+	bases []string
+}
+
+func (p fakeProvider) Name() string                             { return p.name }
+func (p fakeProvider) SupportedBases() []string                 { return p.bases }
+func (fakeProvider) FetchLatest(context.Context) (Rates, error) { return Rates{}, nil }
+func (fakeProvider) FetchHistorical(context.Context, string) (Rates, error) {
+	return Rates{}, nil
+}
+
+func TestProviderFor(t *testing.T) {
+	fake := fakeProvider{name: "fake-test-provider"}
+	registerProvider(fake)
+	defer delete(providers, fake.Name())
+
+	t.Run("resolves a known source", func(t *testing.T) {
+		p, err := providerFor(fake.Name())
+		if err != nil {
+			t.Fatalf("providerFor(%q) returned unexpected error: %v", fake.Name(), err)
+		}
+		if p.Name() != fake.Name() {
+			t.Errorf("providerFor(%q).Name() = %q, want %q", fake.Name(), p.Name(), fake.Name())
+		}
+	})
+
+	t.Run("unknown source errors", func(t *testing.T) {
+		if _, err := providerFor("does-not-exist"); err == nil {
+			t.Error("providerFor(\"does-not-exist\") = nil error, want error")
+		}
+	})
+
+	t.Run("empty source falls back to RATES_PROVIDER env var", func(t *testing.T) {
+		os.Setenv(defaultProviderEnv, fake.Name())
+		defer os.Unsetenv(defaultProviderEnv)
+
+		p, err := providerFor("")
+		if err != nil {
+			t.Fatalf("providerFor(\"\") returned unexpected error: %v", err)
+		}
+		if p.Name() != fake.Name() {
+			t.Errorf("providerFor(\"\").Name() = %q, want %q", p.Name(), fake.Name())
+		}
+	})
+
+	t.Run("empty source and env var falls back to the ECB", func(t *testing.T) {
+		os.Unsetenv(defaultProviderEnv)
+
+		p, err := providerFor("")
+		if err != nil {
+			t.Fatalf("providerFor(\"\") returned unexpected error: %v", err)
+		}
+		if p.Name() != ecbProviderName {
+			t.Errorf("providerFor(\"\").Name() = %q, want %q", p.Name(), ecbProviderName)
+		}
+	})
+}
+
+func TestSupportsBase(t *testing.T) {
+	tests := []struct {
+		name  string
+		bases []string
+		base  string
+		want  bool
+	}{
+		{name: "supported base matches", bases: []string{"EUR"}, base: "EUR", want: true},
+		{name: "unsupported base doesn't match", bases: []string{"USD"}, base: "EUR", want: false},
+		{name: "nil bases never match", bases: nil, base: "EUR", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := fakeProvider{name: "fake-test-provider", bases: tt.bases}
+			if got := supportsBase(p, tt.base); got != tt.want {
+				t.Errorf("supportsBase(%v, %q) = %v, want %v", tt.bases, tt.base, got, tt.want)
+			}
+		})
+	}
+}
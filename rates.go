@@ -18,19 +18,15 @@
 package currencyconverter
 
 import (
-	"encoding/json"
 	"encoding/xml"
 	"fmt"
-	"io/ioutil"
 	"net/http"
+	"strings"
+	"time"
 
 	"google.golang.org/appengine"
-	"google.golang.org/appengine/urlfetch"
 )
 
-// RateURL is the endpoint for European Central Bank rates.
-const RateURL = "https://www.ecb.europa.eu/stats/eurofxref/eurofxref-daily.xml"
-
 // Rate stores a single currency rate as a Go struct.
 type Rate struct {
 	Currency string  `xml:"currency,attr"`
@@ -39,16 +35,20 @@ type Rate struct {
 
 // Rates stores a list of rates. RateList is used for XML and RateMap for JSON.
 type Rates struct {
-	Base     string             `json:"base"`
-	Date     string             `xml:"time,attr" json:"date"`
-	RateList []Rate             `xml:"Cube" json:"-"`
-	RateMap  map[string]float32 `xml:"-" json:"rates"`
+	Base      string             `json:"base"`
+	Date      string             `xml:"time,attr" json:"date"`
+	RateList  []Rate             `xml:"Cube" json:"-"`
+	RateMap   map[string]float32 `xml:"-" json:"rates"`
+	Source    string             `json:"source,omitempty"`
+	FetchedAt time.Time          `json:"fetched_at"`
 }
 
-// Envelope is used by the ECB to wrap the important data.
+// Envelope is used by the ECB to wrap the important data. The daily feed
+// contains a single date Cube; the historical feeds (see history.go) contain
+// one Cube per published date.
 type Envelope struct {
 	XMLName xml.Name `xml:"Envelope"`
-	Rates   Rates    `xml:"Cube>Cube"`
+	Cubes   []Rates  `xml:"Cube>Cube"`
 }
 
 func init() {
@@ -57,50 +57,78 @@ func init() {
 
 func handler(w http.ResponseWriter, r *http.Request) {
 	ctx := appengine.NewContext(r)
-	client := urlfetch.Client(ctx)
 
-	// Fetch rates from ECB.
-	resp, err := client.Get(RateURL)
+	p, err := providerFor(r.URL.Query().Get("source"))
 	if err != nil {
-		http.Error(w, "Error retrieving rates", 500)
+		http.Error(w, err.Error(), 400)
 		return
 	}
 
-	defer resp.Body.Close()
-
-	// Read response body.
-	body, err := ioutil.ReadAll(resp.Body)
+	rates, err := p.FetchLatest(ctx)
 	if err != nil {
-		http.Error(w, "Error reading rates", 500)
+		http.Error(w, "Error retrieving rates", 500)
 		return
 	}
 
-	x := Envelope{}
+	if sc, ok := p.(staleChecker); ok && sc.Stale() {
+		w.Header().Set("X-Rates-Stale", "true")
+	}
 
-	// Unmarshal XML into Go struct.
-	err = xml.Unmarshal(body, &x)
-	if err != nil {
-		http.Error(w, "Error parsing rates", 500)
-		return
+	// Rebase to the requested currency, if any. rebase only knows how to pivot
+	// off a EUR anchor, so this only works for providers that natively quote
+	// EUR.
+	if base := strings.ToUpper(r.URL.Query().Get("base")); base != "" && base != rates.Base {
+		if !supportsBase(p, "EUR") {
+			http.Error(w, fmt.Sprintf("source %q does not support ?base= (natively quotes: %v)", p.Name(), p.SupportedBases()), 400)
+			return
+		}
+
+		rebased, err := rebase(rates.RateMap, base)
+		if err != nil {
+			http.Error(w, err.Error(), 400)
+			return
+		}
+		rates.Base = base
+		rates.RateMap = rebased
 	}
 
-	// Set Base.
-	x.Rates.Base = "EUR"
-	// Read RateList and populate RateMap.
-	x.Rates.RateMap = make(map[string]float32)
-	for _, rate := range x.Rates.RateList {
-		x.Rates.RateMap[rate.Currency] = rate.Rate
+	// Filter down to the requested symbols, if any.
+	if symbols := r.URL.Query().Get("symbols"); symbols != "" {
+		rates.RateMap = filterSymbols(rates.RateMap, strings.Split(symbols, ","))
 	}
 
-	// Serialize to JSON.
-	j, err := json.Marshal(x.Rates)
-	if err != nil {
-		http.Error(w, "Error serializing to JSON", 500)
-		return
+	writeJSON(w, rates)
+}
+
+// rebase converts a EUR-anchored rate map to one anchored on base, dividing
+// every rate by RateMap[base] and injecting the resulting EUR rate. It
+// returns an error if base is not present in rates.
+func rebase(rates map[string]float32, base string) (map[string]float32, error) {
+	baseRate, ok := rates[base]
+	if !ok {
+		return nil, fmt.Errorf("unknown base currency %q", base)
+	}
+
+	rebased := make(map[string]float32, len(rates)+1)
+	for currency, rate := range rates {
+		rebased[currency] = rate / baseRate
+	}
+	rebased["EUR"] = 1 / baseRate
+	delete(rebased, base)
+
+	return rebased, nil
+}
+
+// filterSymbols returns the subset of rates whose currency is in symbols.
+// Unknown symbols are silently ignored.
+func filterSymbols(rates map[string]float32, symbols []string) map[string]float32 {
+	filtered := make(map[string]float32, len(symbols))
+	for _, symbol := range symbols {
+		symbol = strings.ToUpper(strings.TrimSpace(symbol))
+		if rate, ok := rates[symbol]; ok {
+			filtered[symbol] = rate
+		}
 	}
 
-	// Success! Send JSON to client.
-	w.Header().Set("Content-Type", "application/json")
-	w.Header().Set("Cache-Control", "public, max-age=21600")
-	fmt.Fprintf(w, "%v\n", string(j))
+	return filtered
 }
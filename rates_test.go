@@ -0,0 +1,117 @@
+/**
+ *
+ * Copyright 2017 Google Inc. All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package currencyconverter
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRebase(t *testing.T) {
+	tests := []struct {
+		name    string
+		rates   map[string]float32
+		base    string
+		want    map[string]float32
+		wantErr bool
+	}{
+		{
+			name:  "rebases onto a known currency and injects EUR",
+			rates: map[string]float32{"USD": 1.1, "GBP": 0.88, "JPY": 121.0},
+			base:  "USD",
+			want:  map[string]float32{"EUR": 1 / 1.1, "GBP": 0.88 / 1.1, "JPY": 121.0 / 1.1},
+		},
+		{
+			name:    "unknown base currency errors",
+			rates:   map[string]float32{"USD": 1.1},
+			base:    "XYZ",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := rebase(tt.rates, tt.base)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("rebase(%v, %q) = nil error, want error", tt.rates, tt.base)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("rebase(%v, %q) returned unexpected error: %v", tt.rates, tt.base, err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("rebase(%v, %q) = %v, want %v", tt.rates, tt.base, got, tt.want)
+			}
+			for currency, want := range tt.want {
+				got, ok := got[currency]
+				if !ok {
+					t.Errorf("rebase(%v, %q)[%q] missing, want %v", tt.rates, tt.base, currency, want)
+					continue
+				}
+				if math.Abs(float64(got-want)) > 1e-6 {
+					t.Errorf("rebase(%v, %q)[%q] = %v, want %v", tt.rates, tt.base, currency, got, want)
+				}
+			}
+			if _, ok := got[tt.base]; ok {
+				t.Errorf("rebase(%v, %q) kept %q in the result, want it replaced by EUR", tt.rates, tt.base, tt.base)
+			}
+		})
+	}
+}
+
+func TestFilterSymbols(t *testing.T) {
+	rates := map[string]float32{"USD": 1.1, "GBP": 0.88, "JPY": 121.0}
+
+	tests := []struct {
+		name    string
+		symbols []string
+		want    map[string]float32
+	}{
+		{
+			name:    "keeps only the requested symbols",
+			symbols: []string{"usd", " GBP "},
+			want:    map[string]float32{"USD": 1.1, "GBP": 0.88},
+		},
+		{
+			name:    "silently ignores unknown symbols",
+			symbols: []string{"USD", "XYZ"},
+			want:    map[string]float32{"USD": 1.1},
+		},
+		{
+			name:    "empty symbol list filters everything out",
+			symbols: []string{},
+			want:    map[string]float32{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := filterSymbols(rates, tt.symbols)
+			if len(got) != len(tt.want) {
+				t.Fatalf("filterSymbols(%v, %v) = %v, want %v", rates, tt.symbols, got, tt.want)
+			}
+			for currency, want := range tt.want {
+				if got[currency] != want {
+					t.Errorf("filterSymbols(%v, %v)[%q] = %v, want %v", rates, tt.symbols, currency, got[currency], want)
+				}
+			}
+		})
+	}
+}